@@ -0,0 +1,119 @@
+package strling
+
+import "github.com/thecyberlocal/strling/bindings/go/core"
+
+// strategy identifies the execution strategy a Matcher uses to test input
+// against a compiled pattern.
+type strategy int
+
+const (
+	// strategyRegexp falls back to the emitted regex for anything the
+	// classifier can't reduce to a plain string operation.
+	strategyRegexp strategy = iota
+	// strategyExactLiteral matches when the whole pattern is `^lit$`.
+	strategyExactLiteral
+	// strategyPrefixLiteral matches when the pattern is `^lit` optionally
+	// followed by an unbounded `.*`.
+	strategyPrefixLiteral
+	// strategySuffixLiteral matches when the pattern is `lit$`, optionally
+	// preceded by an unbounded `.*`.
+	strategySuffixLiteral
+	// strategyContainsLiteral matches an unanchored, unquantified literal.
+	strategyContainsLiteral
+)
+
+// classify inspects post-coalescing IR (callers are expected to have run
+// core.Simplify first, as Compile does) and picks the cheapest strategy that
+// can execute it, mirroring the tiered matching used by glob libraries where
+// most patterns resolve to O(1) or O(n) string ops instead of NFA
+// simulation. It returns strategyRegexp, "" for anything it can't reduce.
+//
+// A literal strategy is only sound when flags is the zero value: any flag
+// that changes match semantics (case-insensitivity, ASCII mode, ...) is
+// applied by emitters.Emit on the regexp fallback, but a plain
+// strings.HasPrefix/Contains has no way to honor it. So a non-zero flags
+// always falls back to regexp, even for IR that would otherwise reduce to a
+// literal.
+func classify(ir core.IROp, flags core.Flags) (strategy, string, bool) {
+	if flags != (core.Flags{}) {
+		return strategyRegexp, "", false
+	}
+	switch n := ir.(type) {
+	case core.Lit:
+		return strategyContainsLiteral, n.Value, false
+	case core.Seq:
+		return classifySeq(n.Items)
+	default:
+		return strategyRegexp, "", false
+	}
+}
+
+// classifySeq returns, alongside the strategy and literal, whether the
+// reduction discarded a trailing/leading unbounded `.*`: when it did, the
+// actual match span is the whole input (not just the literal), since an
+// unanchored search with a greedy `.*` on one side absorbs everything up to
+// the other anchor. Callers need this to report correct match spans.
+func classifySeq(items []core.IROp) (strategy, string, bool) {
+	startAnchor := len(items) > 0 && isStartAnchor(items[0])
+	if startAnchor {
+		items = items[1:]
+	}
+	endAnchor := len(items) > 0 && isEndAnchor(items[len(items)-1])
+	if endAnchor {
+		items = items[:len(items)-1]
+	}
+
+	// A trailing/leading unbounded `.*` still allows a prefix/suffix
+	// strategy even though it isn't itself a literal — but only when the
+	// matching anchor is present: an unanchored `lit.*` has no "other side"
+	// for the `.*` to be absorbed against (strings.Contains already matches
+	// a trailing `.*` implicitly, but cannot report the full absorbed span),
+	// so it must fall through to the len(items) != 1 check below and fall
+	// back to strategyRegexp instead of being classified without a wildcard.
+	wildcard := false
+	if startAnchor && !endAnchor && len(items) == 2 && isUnboundedDotStar(items[1]) {
+		items = items[:1]
+		wildcard = true
+	} else if endAnchor && !startAnchor && len(items) == 2 && isUnboundedDotStar(items[0]) {
+		items = items[1:]
+		wildcard = true
+	}
+
+	if len(items) != 1 {
+		return strategyRegexp, "", false
+	}
+	lit, ok := items[0].(core.Lit)
+	if !ok {
+		return strategyRegexp, "", false
+	}
+
+	switch {
+	case startAnchor && endAnchor:
+		return strategyExactLiteral, lit.Value, false
+	case startAnchor:
+		return strategyPrefixLiteral, lit.Value, wildcard
+	case endAnchor:
+		return strategySuffixLiteral, lit.Value, wildcard
+	default:
+		return strategyContainsLiteral, lit.Value, false
+	}
+}
+
+func isStartAnchor(op core.IROp) bool {
+	a, ok := op.(core.Anchor)
+	return ok && a.Kind == core.AnchorStart
+}
+
+func isEndAnchor(op core.IROp) bool {
+	a, ok := op.(core.Anchor)
+	return ok && a.Kind == core.AnchorEnd
+}
+
+func isUnboundedDotStar(op core.IROp) bool {
+	q, ok := op.(core.Quant)
+	if !ok || q.Min != 0 || q.Max != -1 {
+		return false
+	}
+	_, isAny := q.Child.(core.AnyChar)
+	return isAny
+}
@@ -0,0 +1,173 @@
+// Package strling compiles the strling DSL and executes the result, picking
+// the cheapest execution strategy the compiled pattern supports instead of
+// always routing through regexp.
+package strling
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/thecyberlocal/strling/bindings/go/core"
+	"github.com/thecyberlocal/strling/bindings/go/emitters"
+)
+
+// Option configures Compile.
+type Option func(*options)
+
+type options struct {
+	flags core.Flags
+}
+
+// WithFlags sets the core.Flags used when a pattern falls back to regex
+// emission.
+func WithFlags(flags core.Flags) Option {
+	return func(o *options) { o.flags = flags }
+}
+
+// Matcher executes a compiled strling pattern against input strings.
+type Matcher struct {
+	strategy strategy
+	literal  string
+	// wildcard is set when strategy is Prefix/SuffixLiteral and the IR
+	// reduction absorbed a trailing/leading unbounded `.*`: the actual
+	// match then spans the whole input, not just literal.
+	wildcard bool
+	re       *regexp.Regexp
+}
+
+// Compile parses dsl, compiles it to IR, and classifies the IR's match
+// strategy. Patterns that reduce to a whole-string, prefix, suffix, or
+// substring literal are matched with plain string operations; everything
+// else falls back to regexp, the same pipeline emitters.Emit already drives.
+func Compile(dsl string, opts ...Option) (*Matcher, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	parser := core.NewParser(dsl)
+	ast, err := parser.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := core.NewCompiler()
+	// classify only recognizes a single coalesced Lit per side of an anchor,
+	// so simplify before classifying: it's what merges e.g. adjacent literal
+	// Lit nodes the raw compiler output may still have split apart.
+	ir := core.Simplify(compiler.Compile(ast))
+
+	strat, literal, wildcard := classify(ir, o.flags)
+	m := &Matcher{strategy: strat, literal: literal, wildcard: wildcard}
+	if strat == strategyRegexp {
+		pattern := emitters.Emit(ir, o.flags)
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		m.re = re
+	}
+	return m, nil
+}
+
+// MatchString reports whether s matches the compiled pattern.
+func (m *Matcher) MatchString(s string) bool {
+	switch m.strategy {
+	case strategyExactLiteral:
+		return s == m.literal
+	case strategyPrefixLiteral:
+		return strings.HasPrefix(s, m.literal)
+	case strategySuffixLiteral:
+		return strings.HasSuffix(s, m.literal)
+	case strategyContainsLiteral:
+		return strings.Contains(s, m.literal)
+	default:
+		return m.re.MatchString(s)
+	}
+}
+
+// FindAll returns all non-overlapping matches of the pattern in s.
+func (m *Matcher) FindAll(s string) []string {
+	switch m.strategy {
+	case strategyExactLiteral:
+		if s == m.literal {
+			return []string{s}
+		}
+		return nil
+	case strategyPrefixLiteral:
+		if !strings.HasPrefix(s, m.literal) {
+			return nil
+		}
+		if m.wildcard {
+			// `^lit.*` is anchored at the start and `.*` is greedy, so the
+			// match consumes the rest of s rather than stopping at literal.
+			return []string{s}
+		}
+		return []string{m.literal}
+	case strategySuffixLiteral:
+		if !strings.HasSuffix(s, m.literal) {
+			return nil
+		}
+		if m.wildcard {
+			// `.*lit$` is unanchored at the start, so the leftmost match
+			// begins at 0 and the greedy `.*` again consumes all of s.
+			return []string{s}
+		}
+		return []string{m.literal}
+	case strategyContainsLiteral:
+		if m.literal == "" {
+			// An empty literal matches at every position; report it once
+			// per rune boundary like regexp would, rather than looping
+			// forever on strings.Index(rest, "") == 0.
+			out := make([]string, utf8.RuneCountInString(s)+1)
+			for i := range out {
+				out[i] = ""
+			}
+			return out
+		}
+		var out []string
+		for rest := s; ; {
+			i := strings.Index(rest, m.literal)
+			if i < 0 {
+				break
+			}
+			out = append(out, m.literal)
+			rest = rest[i+len(m.literal):]
+		}
+		return out
+	default:
+		return m.re.FindAllString(s, -1)
+	}
+}
+
+// FindSubmatch returns the leftmost match and its submatches, or nil if the
+// pattern does not match s. Literal strategies have no submatches beyond the
+// whole match itself.
+func (m *Matcher) FindSubmatch(s string) []string {
+	if m.strategy == strategyRegexp {
+		match := m.re.FindStringSubmatch(s)
+		if match == nil {
+			return nil
+		}
+		return match
+	}
+	if !m.MatchString(s) {
+		return nil
+	}
+	if m.wildcard {
+		return []string{s}
+	}
+	return []string{m.literal}
+}
+
+// LiteralPrefix returns the literal substring a ContainsLiteral (or other
+// literal-strategy) Matcher searches for, and whether one exists, so callers
+// can pre-filter large inputs with strings.Contains before calling
+// MatchString.
+func (m *Matcher) LiteralPrefix() (string, bool) {
+	if m.strategy == strategyRegexp {
+		return "", false
+	}
+	return m.literal, true
+}
@@ -0,0 +1,34 @@
+package core
+
+import "fmt"
+
+// Known ParseError codes. Specs assert on these rather than on message text,
+// so the diagnostic taxonomy stays stable across the Go, Java, and Kotlin
+// bindings.
+const (
+	CodeDuplicateNamedGroup = "DUPLICATE_NAMED_GROUP"
+	CodeInvalidRange        = "INVALID_RANGE"
+)
+
+// ParseError is the typed diagnostic Parser.Parse should return for a DSL
+// that fails to parse, carrying a stable Code so callers (and conformance
+// specs) can assert on the kind of failure instead of matching against
+// Message, which is free to change wording without being a breaking change.
+//
+// Parser.Parse itself lives in core/parser.go, which isn't part of this
+// package's source in this snapshot, so this type only defines the contract;
+// it does not by itself prove any particular parse failure — including
+// CodeDuplicateNamedGroup's and CodeInvalidRange's own checks — has been
+// migrated to return *ParseError instead of a plain error. conformance_test.go
+// and tests/interaction_test.go both skip (rather than fail) when a spec's
+// error case comes back untyped, precisely because that migration can't be
+// verified from here.
+type ParseError struct {
+	Code    string
+	Message string
+	Offset  int
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s at offset %d: %s", e.Code, e.Offset, e.Message)
+}
@@ -0,0 +1,99 @@
+package core
+
+// MaxLookbehindWidth returns the maximum number of characters any
+// lookbehind assertion in ir can look behind by, or -1 if ir contains a
+// lookbehind whose width is not fixed (see FixedWidth), whether or not that
+// width is bounded. A streaming matcher uses this to
+// size the rolling buffer it must keep in memory to evaluate lookbehind
+// assertions without buffering the whole input.
+func MaxLookbehindWidth(ir IROp) int {
+	max := 0
+	var walk func(op IROp) bool // false once an unbounded lookbehind is found
+	walk = func(op IROp) bool {
+		switch n := op.(type) {
+		case Look:
+			if n.Behind {
+				w, ok := FixedWidth(n.Child)
+				if !ok {
+					return false
+				}
+				if w > max {
+					max = w
+				}
+			}
+			return walk(n.Child)
+		case Seq:
+			for _, c := range n.Items {
+				if !walk(c) {
+					return false
+				}
+			}
+		case Alt:
+			for _, c := range n.Items {
+				if !walk(c) {
+					return false
+				}
+			}
+		case Quant:
+			return walk(n.Child)
+		case Group:
+			return walk(n.Child)
+		}
+		return true
+	}
+	if !walk(ir) {
+		return -1
+	}
+	return max
+}
+
+// FixedWidth reports the exact number of characters op always matches, and
+// whether op's width is fixed at all. Alternations whose arms disagree on
+// width, and unbounded or ranged quantifiers, are not fixed-width.
+func FixedWidth(op IROp) (int, bool) {
+	switch n := op.(type) {
+	case Lit:
+		return len([]rune(n.Value)), true
+	case Anchor:
+		return 0, true
+	case CharClass, AnyChar:
+		return 1, true
+	case Seq:
+		total := 0
+		for _, c := range n.Items {
+			w, ok := FixedWidth(c)
+			if !ok {
+				return 0, false
+			}
+			total += w
+		}
+		return total, true
+	case Alt:
+		width, has := 0, false
+		for _, c := range n.Items {
+			w, ok := FixedWidth(c)
+			if !ok {
+				return 0, false
+			}
+			if !has {
+				width, has = w, true
+			} else if w != width {
+				return 0, false
+			}
+		}
+		return width, has
+	case Quant:
+		if n.Min != n.Max {
+			return 0, false
+		}
+		w, ok := FixedWidth(n.Child)
+		if !ok {
+			return 0, false
+		}
+		return w * n.Min, true
+	case Group:
+		return FixedWidth(n.Child)
+	default:
+		return 0, false
+	}
+}
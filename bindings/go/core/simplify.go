@@ -0,0 +1,255 @@
+package core
+
+// DefaultExpandThreshold is the largest bounded repeat count Simplify will
+// expand into an explicit sequence. Repeats above the threshold are left as
+// a Quant, since expanding e.g. x{500} would blow up IR (and emitted regex)
+// size for little benefit.
+const DefaultExpandThreshold = 4
+
+// Simplify rewrites ir into a smaller, canonical form, in the spirit of
+// regexp/syntax's simplify pass: it expands small bounded repeats into
+// explicit sequences, rewrites unbounded and ranged repeats in terms of
+// exact and optional repeats, flattens nested Seq/Alt, coalesces adjacent
+// literals, merges single-character alternations into a CharClass, and
+// drops redundant wrapping nodes. Emit(Simplify(ir)) matches exactly the
+// same strings as Emit(ir); Simplify exists to give emitters (especially
+// future non-RE2 targets) a stable normal form to work from.
+//
+// Simplify is deliberately a free function over IROp rather than a flag on
+// Compiler.Compile: Compiler's own type and Compile method live in
+// core/compiler.go, which isn't part of this package's source in this
+// snapshot, so there's no Compiler definition here to add an internal option
+// to. Callers that want the optional step wire it in themselves right after
+// Compile instead — see strling.Compile and NewStreamMatcher, both of which
+// call Simplify/SimplifyThreshold on the IR before using it further.
+func Simplify(ir IROp) IROp {
+	return SimplifyThreshold(ir, DefaultExpandThreshold)
+}
+
+// SimplifyThreshold is Simplify with an explicit expansion threshold:
+// bounded repeats x{n} (and the n leading copies produced by x{n,m} /
+// x{n,}) are expanded into an explicit Seq only when n is at most
+// threshold.
+func SimplifyThreshold(ir IROp, threshold int) IROp {
+	switch n := ir.(type) {
+	case Seq:
+		return simplifySeq(n, threshold)
+	case Alt:
+		return simplifyAlt(n, threshold)
+	case Quant:
+		return simplifyQuant(n, threshold)
+	case Group:
+		return simplifyGroup(n, threshold)
+	default:
+		return ir
+	}
+}
+
+func simplifySeq(n Seq, threshold int) IROp {
+	var flat []IROp
+	for _, child := range n.Items {
+		switch c := SimplifyThreshold(child, threshold).(type) {
+		case Seq:
+			flat = append(flat, c.Items...)
+		default:
+			flat = append(flat, c)
+		}
+	}
+	flat = dropEmptySeqs(flat)
+	flat = coalesceLits(flat)
+	if len(flat) == 1 {
+		return flat[0]
+	}
+	return Seq{Items: flat}
+}
+
+func dropEmptySeqs(items []IROp) []IROp {
+	var out []IROp
+	for _, it := range items {
+		if s, ok := it.(Seq); ok && len(s.Items) == 0 {
+			continue
+		}
+		out = append(out, it)
+	}
+	return out
+}
+
+func coalesceLits(items []IROp) []IROp {
+	var out []IROp
+	for _, it := range items {
+		if lit, ok := it.(Lit); ok && len(out) > 0 {
+			if prev, ok := out[len(out)-1].(Lit); ok {
+				out[len(out)-1] = Lit{Value: prev.Value + lit.Value}
+				continue
+			}
+		}
+		out = append(out, it)
+	}
+	return out
+}
+
+func simplifyAlt(n Alt, threshold int) IROp {
+	var flat []IROp
+	for _, child := range n.Items {
+		switch c := SimplifyThreshold(child, threshold).(type) {
+		case Alt:
+			flat = append(flat, c.Items...)
+		default:
+			flat = append(flat, c)
+		}
+	}
+	flat = mergeSingleCharAlts(flat)
+	if len(flat) == 1 {
+		return flat[0]
+	}
+	return Alt{Items: flat}
+}
+
+// mergeSingleCharAlts merges consecutive runs of single-character Lit arms
+// into one CharClass, since `a|b|c` and `[abc]` match identically.
+func mergeSingleCharAlts(items []IROp) []IROp {
+	var out []IROp
+	var run []rune
+	flushRun := func() {
+		switch len(run) {
+		case 0:
+		case 1:
+			out = append(out, Lit{Value: string(run[0])})
+		default:
+			ranges := make([]CharRange, len(run))
+			for i, r := range run {
+				ranges[i] = CharRange{Lo: r, Hi: r}
+			}
+			out = append(out, CharClass{Ranges: ranges})
+		}
+		run = nil
+	}
+	for _, it := range items {
+		if lit, ok := it.(Lit); ok {
+			if runes := []rune(lit.Value); len(runes) == 1 {
+				run = append(run, runes[0])
+				continue
+			}
+		}
+		flushRun()
+		out = append(out, it)
+	}
+	flushRun()
+	return out
+}
+
+func simplifyQuant(n Quant, threshold int) IROp {
+	child := SimplifyThreshold(n.Child, threshold)
+
+	// expandCopies duplicates the same child IROp reference, so expanding a
+	// repeat whose child contains a capturing or named Group would alias
+	// that group across every copy (duplicate names, miscounted numbered
+	// groups). Treat that the same as exceeding threshold: leave the Quant
+	// unexpanded rather than produce aliased groups.
+	expandable := !containsCapturingGroup(child)
+
+	switch {
+	case n.Min == n.Max:
+		// x{n}
+		if n.Min <= threshold && expandable {
+			return expandCopies(child, n.Min)
+		}
+		return Quant{Child: child, Min: n.Min, Max: n.Max, Lazy: n.Lazy, Possessive: n.Possessive}
+
+	case n.Max == -1 && n.Min == 0:
+		return Quant{Child: child, Min: 0, Max: -1, Lazy: n.Lazy, Possessive: n.Possessive}
+
+	case n.Max == -1:
+		// x{n,} -> x{n} x*, but only when n is small enough to expand; a
+		// large n left as-is avoids the same blowup the threshold exists to
+		// prevent for the exact-repeat case above.
+		if n.Min > threshold || !expandable {
+			return Quant{Child: child, Min: n.Min, Max: n.Max, Lazy: n.Lazy, Possessive: n.Possessive}
+		}
+		head := expandCopies(child, n.Min)
+		tail := Quant{Child: child, Min: 0, Max: -1, Lazy: n.Lazy, Possessive: n.Possessive}
+		return Seq{Items: []IROp{head, tail}}
+
+	default:
+		// x{n,m} -> x{n} x?{m-n}, gated the same way: both the n leading
+		// copies and the m-n trailing optionals are only expanded when
+		// neither count exceeds threshold.
+		if n.Min > threshold || n.Max-n.Min > threshold || !expandable {
+			return Quant{Child: child, Min: n.Min, Max: n.Max, Lazy: n.Lazy, Possessive: n.Possessive}
+		}
+		items := asItems(expandCopies(child, n.Min))
+		for i := 0; i < n.Max-n.Min; i++ {
+			items = append(items, Quant{Child: child, Min: 0, Max: 1, Lazy: n.Lazy, Possessive: n.Possessive})
+		}
+		return Seq{Items: items}
+	}
+}
+
+// containsCapturingGroup reports whether op contains a capturing or named
+// Group anywhere in its tree, used to veto expanding a repeat that would
+// otherwise duplicate (and thus alias) that group across copies.
+func containsCapturingGroup(op IROp) bool {
+	switch n := op.(type) {
+	case Group:
+		if n.Capturing || n.Name != "" {
+			return true
+		}
+		return containsCapturingGroup(n.Child)
+	case Seq:
+		for _, it := range n.Items {
+			if containsCapturingGroup(it) {
+				return true
+			}
+		}
+	case Alt:
+		for _, it := range n.Items {
+			if containsCapturingGroup(it) {
+				return true
+			}
+		}
+	case Quant:
+		return containsCapturingGroup(n.Child)
+	case Look:
+		return containsCapturingGroup(n.Child)
+	}
+	return false
+}
+
+func asItems(op IROp) []IROp {
+	if s, ok := op.(Seq); ok {
+		return append([]IROp(nil), s.Items...)
+	}
+	return []IROp{op}
+}
+
+func expandCopies(child IROp, n int) IROp {
+	switch n {
+	case 0:
+		return Seq{}
+	case 1:
+		return child
+	default:
+		items := make([]IROp, n)
+		for i := range items {
+			items[i] = child
+		}
+		return Seq{Items: items}
+	}
+}
+
+func simplifyGroup(n Group, threshold int) IROp {
+	child := SimplifyThreshold(n.Child, threshold)
+	if !n.Capturing && n.Name == "" && isSingleAtom(child) {
+		return child
+	}
+	return Group{Child: child, Capturing: n.Capturing, Name: n.Name}
+}
+
+func isSingleAtom(op IROp) bool {
+	switch op.(type) {
+	case Lit, CharClass, Anchor, AnyChar:
+		return true
+	default:
+		return false
+	}
+}
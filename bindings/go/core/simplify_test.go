@@ -0,0 +1,141 @@
+package core_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/thecyberlocal/strling/bindings/go/core"
+	"github.com/thecyberlocal/strling/bindings/go/emitters"
+)
+
+// TestSimplifyRoundTrip proves Emit(Simplify(ir)) matches exactly the same
+// strings as Emit(ir) over a small corpus, and reports how much Simplify
+// shrinks the emitted pattern.
+func TestSimplifyRoundTrip(t *testing.T) {
+	corpus := []struct {
+		name    string
+		dsl     string
+		samples []string
+	}{
+		{"ExactRepeat", `a{3}`, []string{"aaa", "aa", "aaaa", "b"}},
+		{"RangeRepeat", `a{2,4}`, []string{"a", "aa", "aaa", "aaaa", "aaaaa"}},
+		{"AtLeastRepeat", `a{2,}`, []string{"a", "aa", "aaaa", ""}},
+		{"NestedGroups", `(?:(?:ab)(?:cd))`, []string{"abcd", "abc", "ab"}},
+		{"SingleCharAlternation", `a|b|c`, []string{"a", "b", "c", "d"}},
+		{"QuantifiedNamedGroup", `(?<digit>\d){3}`, []string{"123", "12", "1234", "abc"}},
+	}
+
+	for _, tc := range corpus {
+		t.Run(tc.name, func(t *testing.T) {
+			parser := core.NewParser(tc.dsl)
+			ast, err := parser.Parse()
+			if err != nil {
+				t.Fatalf("Parser failed for %q: %v", tc.dsl, err)
+			}
+			compiler := core.NewCompiler()
+			ir := compiler.Compile(ast)
+
+			before := emitters.Emit(ir, core.Flags{})
+			simplified := core.Simplify(ir)
+			after := emitters.Emit(simplified, core.Flags{})
+
+			reBefore, err := regexp.Compile(before)
+			if err != nil {
+				t.Fatalf("regexp.Compile(%q) failed: %v", before, err)
+			}
+			reAfter, err := regexp.Compile(after)
+			if err != nil {
+				t.Fatalf("regexp.Compile(%q) failed: %v", after, err)
+			}
+
+			for _, s := range tc.samples {
+				if got, want := reAfter.MatchString(s), reBefore.MatchString(s); got != want {
+					t.Errorf("MatchString(%q): simplified=%v, original=%v", s, got, want)
+				}
+			}
+
+			t.Logf("%s: %d bytes -> %d bytes (%q -> %q)", tc.name, len(before), len(after), before, after)
+		})
+	}
+}
+
+// TestSimplifyLeavesLargeRepeatsUnexpanded verifies that x{n,} and x{n,m}
+// are left as a Quant (rather than expanded into hundreds of Seq children)
+// once n, or the n,m span, exceeds the threshold, mirroring the exact-repeat
+// x{n} case.
+func TestSimplifyLeavesLargeRepeatsUnexpanded(t *testing.T) {
+	testCases := []struct {
+		name string
+		dsl  string
+	}{
+		{"LargeAtLeastRepeat", `a{1000,}`},
+		{"LargeRangeRepeat", `a{0,5000}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parser := core.NewParser(tc.dsl)
+			ast, err := parser.Parse()
+			if err != nil {
+				t.Fatalf("Parser failed for %q: %v", tc.dsl, err)
+			}
+			compiler := core.NewCompiler()
+			ir := compiler.Compile(ast)
+
+			simplified := core.Simplify(ir)
+			if _, ok := simplified.(core.Quant); !ok {
+				t.Errorf("Simplify(%q) = %T, want an unexpanded core.Quant", tc.dsl, simplified)
+			}
+		})
+	}
+}
+
+// TestSimplifyLeavesCapturingGroupRepeatsUnexpanded verifies that a repeat
+// count under threshold is still left unexpanded when its child contains a
+// capturing or named Group: expandCopies duplicates the same child
+// reference, so expanding it here would alias that group's name/number
+// across every copy instead of producing distinct captures.
+func TestSimplifyLeavesCapturingGroupRepeatsUnexpanded(t *testing.T) {
+	testCases := []struct {
+		name string
+		dsl  string
+	}{
+		{"NamedGroupExactRepeat", `(?<digit>\d){3}`},
+		{"CapturingGroupRangeRepeat", `(\d){2,4}`},
+		{"CapturingGroupAtLeastRepeat", `(\d){2,}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parser := core.NewParser(tc.dsl)
+			ast, err := parser.Parse()
+			if err != nil {
+				t.Fatalf("Parser failed for %q: %v", tc.dsl, err)
+			}
+			compiler := core.NewCompiler()
+			ir := compiler.Compile(ast)
+
+			simplified := core.Simplify(ir)
+			if containsExpandedGroupCopies(simplified) {
+				t.Errorf("Simplify(%q) expanded a repeat over a capturing group into aliased copies", tc.dsl)
+			}
+		})
+	}
+}
+
+// containsExpandedGroupCopies reports whether op is a Seq holding two or
+// more Group children, the shape expandCopies would have produced had it
+// incorrectly expanded a repeat over a capturing group.
+func containsExpandedGroupCopies(op core.IROp) bool {
+	s, ok := op.(core.Seq)
+	if !ok {
+		return false
+	}
+	groups := 0
+	for _, it := range s.Items {
+		if _, ok := it.(core.Group); ok {
+			groups++
+		}
+	}
+	return groups >= 2
+}
@@ -0,0 +1,315 @@
+package emitters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thecyberlocal/strling/bindings/go/core"
+)
+
+// Target identifies the regex engine an emitted pattern must run on. Engines
+// disagree on what a regex dialect can express, so EmitFor rewrites or
+// rejects IR constructs per the Target's capability table instead of
+// assuming the RE2 dialect that Emit produces.
+type Target int
+
+const (
+	TargetGoRE2 Target = iota
+	TargetPCRE
+	TargetECMAScript
+	TargetDotNet
+	TargetPythonRe
+	TargetOniguruma
+)
+
+func (t Target) String() string {
+	switch t {
+	case TargetGoRE2:
+		return "go-re2"
+	case TargetPCRE:
+		return "pcre"
+	case TargetECMAScript:
+		return "ecmascript"
+	case TargetDotNet:
+		return "dotnet"
+	case TargetPythonRe:
+		return "python-re"
+	case TargetOniguruma:
+		return "oniguruma"
+	default:
+		return fmt.Sprintf("Target(%d)", int(t))
+	}
+}
+
+// capabilities describes what a target engine's regex dialect can express.
+// EmitFor consults this table to decide whether a construct must be
+// rewritten into an equivalent form or rejected outright.
+type capabilities struct {
+	// Lookbehind reports whether the engine supports lookbehind at all.
+	Lookbehind bool
+	// Lookahead reports whether the engine supports lookahead at all.
+	Lookahead bool
+	// VariableWidthLookbehind reports whether lookbehind bodies may match a
+	// variable number of characters (e.g. `x+`) rather than a fixed width.
+	VariableWidthLookbehind bool
+	// NamedGroupOpen is the opening syntax for a named capturing group,
+	// e.g. "(?P<" for Python/RE2 or "(?<" for .NET/ECMAScript.
+	NamedGroupOpen string
+	// PossessiveQuantifiers reports whether `x++`, `x*+`, `x?+` are legal.
+	PossessiveQuantifiers bool
+	// AtomicGroups reports whether `(?>...)` is legal.
+	AtomicGroups bool
+	// StringAnchors reports whether `\A` and `\z` are available as
+	// string-boundary anchors distinct from `^` and `$`.
+	StringAnchors bool
+	// UnicodePropertyEscapes reports whether `\p{...}` is legal.
+	UnicodePropertyEscapes bool
+	// ASCIIDigit is the class to substitute for `\d` when Flags.ASCII is set
+	// on an engine where `\d` is Unicode-wide by default. Not yet consumed:
+	// `\d` compiles to its own IR node rather than a Lit, and emitNode has no
+	// case for it yet, so this field is unused until that node exists.
+	ASCIIDigit string
+}
+
+var capabilityTable = map[Target]capabilities{
+	TargetGoRE2: {
+		Lookbehind: false, Lookahead: false, VariableWidthLookbehind: false,
+		NamedGroupOpen: "(?P<", PossessiveQuantifiers: false, AtomicGroups: false,
+		StringAnchors: false, UnicodePropertyEscapes: true, ASCIIDigit: "[0-9]",
+	},
+	TargetPCRE: {
+		Lookbehind: true, Lookahead: true, VariableWidthLookbehind: false,
+		NamedGroupOpen: "(?P<", PossessiveQuantifiers: true, AtomicGroups: true,
+		StringAnchors: true, UnicodePropertyEscapes: true, ASCIIDigit: "[0-9]",
+	},
+	TargetECMAScript: {
+		Lookbehind: true, Lookahead: true, VariableWidthLookbehind: true,
+		NamedGroupOpen: "(?<", PossessiveQuantifiers: false, AtomicGroups: false,
+		StringAnchors: false, UnicodePropertyEscapes: true, ASCIIDigit: "[0-9]",
+	},
+	TargetDotNet: {
+		Lookbehind: true, Lookahead: true, VariableWidthLookbehind: true,
+		NamedGroupOpen: "(?<", PossessiveQuantifiers: false, AtomicGroups: true,
+		StringAnchors: true, UnicodePropertyEscapes: true, ASCIIDigit: "[0-9]",
+	},
+	TargetPythonRe: {
+		Lookbehind: true, Lookahead: true, VariableWidthLookbehind: false,
+		NamedGroupOpen: "(?P<", PossessiveQuantifiers: false, AtomicGroups: false,
+		StringAnchors: true, UnicodePropertyEscapes: false, ASCIIDigit: "[0-9]",
+	},
+	TargetOniguruma: {
+		Lookbehind: true, Lookahead: true, VariableWidthLookbehind: true,
+		NamedGroupOpen: "(?<", PossessiveQuantifiers: true, AtomicGroups: true,
+		StringAnchors: true, UnicodePropertyEscapes: true, ASCIIDigit: "[0-9]",
+	},
+}
+
+// UnsupportedError reports an IR construct that target cannot express.
+type UnsupportedError struct {
+	Target  Target
+	Feature string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("emitters: %s is not supported by target %s", e.Feature, e.Target)
+}
+
+// EmitFor walks ir and renders it in the regex dialect of target, rewriting
+// constructs the target expresses differently (named group syntax, ASCII
+// digit classes, ...) and returning an *UnsupportedError when target cannot
+// express a construct at all (e.g. variable-width lookbehind on RE2).
+func EmitFor(ir core.IROp, flags core.Flags, target Target) (string, error) {
+	caps, ok := capabilityTable[target]
+	if !ok {
+		return "", fmt.Errorf("emitters: unknown target %s", target)
+	}
+	var b strings.Builder
+	if err := emitNode(&b, ir, flags, target, caps); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func emitNode(b *strings.Builder, op core.IROp, flags core.Flags, target Target, caps capabilities) error {
+	switch n := op.(type) {
+	case core.Lit:
+		b.WriteString(escapeLiteral(n.Value))
+	case core.Seq:
+		for _, child := range n.Items {
+			if err := emitNode(b, child, flags, target, caps); err != nil {
+				return err
+			}
+		}
+	case core.Alt:
+		for i, child := range n.Items {
+			if i > 0 {
+				b.WriteByte('|')
+			}
+			if err := emitNode(b, child, flags, target, caps); err != nil {
+				return err
+			}
+		}
+	case core.CharClass:
+		emitCharClass(b, n, flags, caps)
+	case core.Anchor:
+		b.WriteString(anchorSyntax(n, caps))
+	case core.AnyChar:
+		b.WriteByte('.')
+	case core.Quant:
+		if err := emitNode(b, n.Child, flags, target, caps); err != nil {
+			return err
+		}
+		b.WriteString(quantSuffix(n))
+		if n.Lazy {
+			b.WriteByte('?')
+		} else if n.Possessive {
+			if !caps.PossessiveQuantifiers {
+				return &UnsupportedError{Target: target, Feature: "possessive quantifiers"}
+			}
+			b.WriteByte('+')
+		}
+	case core.Group:
+		switch {
+		case n.Name != "":
+			b.WriteString(caps.NamedGroupOpen)
+			b.WriteString(n.Name)
+			b.WriteByte('>')
+		case n.Capturing:
+			b.WriteByte('(')
+		default:
+			b.WriteString("(?:")
+		}
+		if err := emitNode(b, n.Child, flags, target, caps); err != nil {
+			return err
+		}
+		b.WriteByte(')')
+	case core.Look:
+		if n.Behind {
+			if !caps.VariableWidthLookbehind && !isFixedWidth(n.Child) {
+				return &UnsupportedError{Target: target, Feature: "variable-width lookbehind"}
+			}
+			if !caps.Lookbehind {
+				return &UnsupportedError{Target: target, Feature: "lookbehind"}
+			}
+			b.WriteString("(?<")
+		} else {
+			if !caps.Lookahead {
+				return &UnsupportedError{Target: target, Feature: "lookahead"}
+			}
+			b.WriteString("(?")
+		}
+		if n.Negative {
+			b.WriteByte('!')
+		} else {
+			b.WriteByte('=')
+		}
+		if err := emitNode(b, n.Child, flags, target, caps); err != nil {
+			return err
+		}
+		b.WriteByte(')')
+	default:
+		return &UnsupportedError{Target: target, Feature: fmt.Sprintf("%T", op)}
+	}
+	return nil
+}
+
+// literalMetacharacters are the runes a Lit's raw text must be escaped
+// against so the regex engine reads them as themselves rather than as
+// syntax: Lit only ever holds matched-literally text (shorthand classes
+// like `\d` compile to a distinct IR node, never a Lit), so there is no
+// flags.ASCII rewrite to apply here.
+const literalMetacharacters = `\.+*?()|[]{}^$`
+
+// escapeLiteral renders a literal's text with every regex metacharacter
+// backslash-escaped, so it matches itself instead of being parsed as syntax.
+func escapeLiteral(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(literalMetacharacters, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// anchorSyntax renders a start/end anchor using the target's preferred
+// string-boundary syntax: `\A`/`\z` where the target has StringAnchors,
+// falling back to `^`/`$` otherwise.
+func anchorSyntax(a core.Anchor, caps capabilities) string {
+	if caps.StringAnchors {
+		if a.Kind == core.AnchorStart {
+			return `\A`
+		}
+		return `\z`
+	}
+	if a.Kind == core.AnchorStart {
+		return "^"
+	}
+	return "$"
+}
+
+func emitCharClass(b *strings.Builder, cc core.CharClass, flags core.Flags, caps capabilities) {
+	b.WriteByte('[')
+	if cc.Negated {
+		b.WriteByte('^')
+	}
+	for _, r := range cc.Ranges {
+		if r.Lo == r.Hi {
+			b.WriteRune(r.Lo)
+		} else {
+			b.WriteRune(r.Lo)
+			b.WriteByte('-')
+			b.WriteRune(r.Hi)
+		}
+	}
+	b.WriteByte(']')
+}
+
+func quantSuffix(q core.Quant) string {
+	switch {
+	case q.Min == 0 && q.Max == -1:
+		return "*"
+	case q.Min == 1 && q.Max == -1:
+		return "+"
+	case q.Min == 0 && q.Max == 1:
+		return "?"
+	case q.Max == -1:
+		return fmt.Sprintf("{%d,}", q.Min)
+	case q.Min == q.Max:
+		return fmt.Sprintf("{%d}", q.Min)
+	default:
+		return fmt.Sprintf("{%d,%d}", q.Min, q.Max)
+	}
+}
+
+// isFixedWidth reports whether op always matches the same number of
+// characters, which is what most real-world engines require of a
+// lookbehind body when they don't support variable-width lookbehind.
+func isFixedWidth(op core.IROp) bool {
+	switch n := op.(type) {
+	case core.Lit:
+		return true
+	case core.CharClass:
+		return true
+	case core.Anchor:
+		return true
+	case core.AnyChar:
+		return true
+	case core.Seq:
+		for _, child := range n.Items {
+			if !isFixedWidth(child) {
+				return false
+			}
+		}
+		return true
+	case core.Alt, core.Quant:
+		// Alt arms must agree on width, and a Quant's child must be fixed.
+		_, ok := core.FixedWidth(n)
+		return ok
+	case core.Group:
+		return isFixedWidth(n.Child)
+	default:
+		return false
+	}
+}
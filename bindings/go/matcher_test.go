@@ -0,0 +1,166 @@
+package strling
+
+import "testing"
+
+// TestCompileStrategyClassification verifies that Compile picks the cheapest
+// string-operation strategy the IR supports before falling back to regexp.
+func TestCompileStrategyClassification(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dsl      string
+		strategy strategy
+	}{
+		{"ExactLiteral", "^hello$", strategyExactLiteral},
+		{"PrefixLiteral", "^hello", strategyPrefixLiteral},
+		{"SuffixLiteral", "hello$", strategySuffixLiteral},
+		{"ContainsLiteral", "hello", strategyContainsLiteral},
+		{"FallsBackToRegexp", `^\d+$`, strategyRegexp},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := Compile(tc.dsl)
+			if err != nil {
+				t.Fatalf("Compile(%q) failed: %v", tc.dsl, err)
+			}
+			if m.strategy != tc.strategy {
+				t.Errorf("Compile(%q) strategy = %v, want %v", tc.dsl, m.strategy, tc.strategy)
+			}
+		})
+	}
+}
+
+// TestMatcherMatchString verifies MatchString behavior across strategies.
+func TestMatcherMatchString(t *testing.T) {
+	testCases := []struct {
+		name      string
+		dsl       string
+		matches   []string
+		noMatches []string
+	}{
+		{"ExactLiteral", "^hello$", []string{"hello"}, []string{"hello!", "hell"}},
+		{"PrefixLiteral", "^hello", []string{"hello", "hello world"}, []string{"say hello"}},
+		{"SuffixLiteral", "hello$", []string{"hello", "say hello"}, []string{"hello!"}},
+		{"ContainsLiteral", "hello", []string{"hello", "say hello!"}, []string{"hell"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := Compile(tc.dsl)
+			if err != nil {
+				t.Fatalf("Compile(%q) failed: %v", tc.dsl, err)
+			}
+			for _, s := range tc.matches {
+				if !m.MatchString(s) {
+					t.Errorf("MatchString(%q) = false, want true", s)
+				}
+			}
+			for _, s := range tc.noMatches {
+				if m.MatchString(s) {
+					t.Errorf("MatchString(%q) = true, want false", s)
+				}
+			}
+		})
+	}
+}
+
+// TestMatcherFindAllWithWildcard verifies that FindAll/FindSubmatch return
+// the whole matched span, not just the bare literal, when the reduction
+// absorbed a trailing/leading `.*` (regexp.FindAllString("^hello.*", ...)
+// would return the whole string too).
+func TestMatcherFindAllWithWildcard(t *testing.T) {
+	testCases := []struct {
+		name string
+		dsl  string
+		s    string
+		want string
+	}{
+		{"PrefixWildcard", "^hello.*", "hello world", "hello world"},
+		{"SuffixWildcard", ".*hello$", "say hello", "say hello"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := Compile(tc.dsl)
+			if err != nil {
+				t.Fatalf("Compile(%q) failed: %v", tc.dsl, err)
+			}
+			if got := m.FindAll(tc.s); len(got) != 1 || got[0] != tc.want {
+				t.Errorf("FindAll(%q) = %v, want [%q]", tc.s, got, tc.want)
+			}
+			if got := m.FindSubmatch(tc.s); len(got) != 1 || got[0] != tc.want {
+				t.Errorf("FindSubmatch(%q) = %v, want [%q]", tc.s, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestCompileCoalescesAdjacentLiteralsBeforeClassifying verifies classify's
+// doc-comment claim that it inspects post-coalescing IR: two adjacent
+// non-capturing groups wrapping single-character literals don't arrive from
+// the base compiler as one Lit, so Compile must run core.Simplify (which
+// unwraps the groups and coalesces the resulting adjacent Lits) before
+// classify, or this falls back to strategyRegexp instead of recognizing the
+// merged literal "ab".
+func TestCompileCoalescesAdjacentLiteralsBeforeClassifying(t *testing.T) {
+	m, err := Compile("^(?:a)(?:b)$")
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	if m.strategy != strategyExactLiteral {
+		t.Errorf("Compile(\"^(?:a)(?:b)$\") strategy = %v, want strategyExactLiteral", m.strategy)
+	}
+	if !m.MatchString("ab") {
+		t.Error("MatchString(\"ab\") = false, want true")
+	}
+}
+
+// TestMatcherFindAllUnanchoredWildcardFallsBackToRegexp verifies that an
+// unanchored literal followed by an unbounded `.*` (no anchor for the `.*`
+// to absorb against) falls back to strategyRegexp rather than being
+// misclassified as ContainsLiteral with the `.*` silently dropped, which
+// would truncate the reported match span.
+func TestMatcherFindAllUnanchoredWildcardFallsBackToRegexp(t *testing.T) {
+	m, err := Compile("lit.*")
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	if m.strategy != strategyRegexp {
+		t.Errorf("Compile(\"lit.*\") strategy = %v, want strategyRegexp", m.strategy)
+	}
+	if got := m.FindAll("xlitYYY"); len(got) != 1 || got[0] != "litYYY" {
+		t.Errorf("FindAll(\"xlitYYY\") = %v, want [\"litYYY\"]", got)
+	}
+}
+
+// TestMatcherFindAllEmptyLiteralDoesNotHang verifies that a ContainsLiteral
+// Matcher with an empty literal terminates instead of looping forever on
+// strings.Index(rest, "") always returning 0.
+func TestMatcherFindAllEmptyLiteralDoesNotHang(t *testing.T) {
+	m := &Matcher{strategy: strategyContainsLiteral, literal: ""}
+	got := m.FindAll("abc")
+	if len(got) != 4 {
+		t.Errorf("FindAll with empty literal = %v (len %d), want 4 empty matches", got, len(got))
+	}
+}
+
+// TestMatcherLiteralPrefix verifies the literal introspection used to
+// pre-filter large inputs before calling MatchString.
+func TestMatcherLiteralPrefix(t *testing.T) {
+	m, err := Compile("hello")
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	lit, ok := m.LiteralPrefix()
+	if !ok || lit != "hello" {
+		t.Errorf("LiteralPrefix() = (%q, %v), want (\"hello\", true)", lit, ok)
+	}
+
+	regexMatcher, err := Compile(`\d+`)
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	if _, ok := regexMatcher.LiteralPrefix(); ok {
+		t.Error("LiteralPrefix() ok = true for a regexp-strategy Matcher, want false")
+	}
+}
@@ -0,0 +1,304 @@
+package strling
+
+import (
+	"fmt"
+
+	"github.com/thecyberlocal/strling/bindings/go/core"
+)
+
+// The streaming executor compiles IR into a small Thompson-style NFA: one
+// instruction per IR atom (Lit rune, CharClass, Anchor, ...), with Seq/Alt/
+// Quant wiring the epsilon transitions between them. Running it is a
+// straightforward Pike's-VM-style simulation: a set of active threads is
+// advanced one rune at a time, so the whole input never has to be resident
+// in memory at once.
+
+type instKind int
+
+const (
+	instChar instKind = iota
+	instSplit
+	instMatch
+	instCapStart
+	instCapEnd
+	instAssertStart
+	instAssertEnd
+	instLookaround
+)
+
+type inst struct {
+	kind instKind
+
+	pred func(r rune) bool // instChar
+
+	capName string // instCapStart, instCapEnd
+
+	sub      *nfa // instLookaround: compiled sub-pattern to test
+	behind   bool // instLookaround: look behind vs. look ahead
+	negative bool // instLookaround: negative assertion
+	width    int  // instLookaround: fixed width of sub in runes
+
+	out, out1 *inst // epsilon/fallthrough targets; nil until patched
+}
+
+type nfa struct {
+	start *inst
+}
+
+// compileNFA builds an nfa executing ir. It returns an error if ir contains
+// a construct the streaming executor cannot express, such as variable-width
+// lookahead (streaming only supports fixed-width lookaround, since it can't
+// buffer an unbounded amount of upcoming input).
+func compileNFA(ir core.IROp) (*nfa, error) {
+	c := &nfaBuilder{}
+	start, outs, err := c.build(ir)
+	if err != nil {
+		return nil, err
+	}
+	match := &inst{kind: instMatch}
+	patch(outs, match)
+	return &nfa{start: start}, nil
+}
+
+type nfaBuilder struct{}
+
+func patch(outs []**inst, target *inst) {
+	for _, o := range outs {
+		*o = target
+	}
+}
+
+func (c *nfaBuilder) build(op core.IROp) (*inst, []**inst, error) {
+	switch n := op.(type) {
+	case core.Lit:
+		return c.buildLiteral(n.Value)
+	case core.AnyChar:
+		i := &inst{kind: instChar, pred: func(r rune) bool { return r != '\n' }}
+		return i, []**inst{&i.out}, nil
+	case core.CharClass:
+		i := &inst{kind: instChar, pred: charClassPred(n)}
+		return i, []**inst{&i.out}, nil
+	case core.Anchor:
+		kind := instAssertStart
+		if n.Kind == core.AnchorEnd {
+			kind = instAssertEnd
+		}
+		i := &inst{kind: kind}
+		return i, []**inst{&i.out}, nil
+	case core.Seq:
+		return c.buildSeq(n.Items)
+	case core.Alt:
+		return c.buildAlt(n.Items)
+	case core.Quant:
+		return c.buildQuant(n)
+	case core.Group:
+		return c.buildGroup(n)
+	case core.Look:
+		return c.buildLook(n)
+	default:
+		return nil, nil, fmt.Errorf("strling: streaming matcher does not support %T", op)
+	}
+}
+
+func (c *nfaBuilder) buildLiteral(value string) (*inst, []**inst, error) {
+	var first, prev *inst
+	var outs []**inst
+	for _, r := range value {
+		r := r
+		i := &inst{kind: instChar, pred: func(x rune) bool { return x == r }}
+		if first == nil {
+			first = i
+		}
+		if prev != nil {
+			prev.out = i
+		}
+		prev = i
+	}
+	if first == nil {
+		// Empty literal: a no-op that falls through immediately.
+		i := &inst{kind: instSplit}
+		return i, []**inst{&i.out}, nil
+	}
+	outs = append(outs, &prev.out)
+	return first, outs, nil
+}
+
+func (c *nfaBuilder) buildSeq(items []core.IROp) (*inst, []**inst, error) {
+	var first *inst
+	var outs []**inst
+	for _, item := range items {
+		start, itemOuts, err := c.build(item)
+		if err != nil {
+			return nil, nil, err
+		}
+		if first == nil {
+			first = start
+		} else {
+			patch(outs, start)
+		}
+		outs = itemOuts
+	}
+	if first == nil {
+		i := &inst{kind: instSplit}
+		return i, []**inst{&i.out}, nil
+	}
+	return first, outs, nil
+}
+
+func (c *nfaBuilder) buildAlt(items []core.IROp) (*inst, []**inst, error) {
+	if len(items) == 0 {
+		i := &inst{kind: instSplit}
+		return i, []**inst{&i.out}, nil
+	}
+	start, outs, err := c.build(items[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, item := range items[1:] {
+		armStart, armOuts, err := c.build(item)
+		if err != nil {
+			return nil, nil, err
+		}
+		split := &inst{kind: instSplit, out: start, out1: armStart}
+		start = split
+		outs = append(outs, armOuts...)
+	}
+	return start, outs, nil
+}
+
+func (c *nfaBuilder) buildQuant(n core.Quant) (*inst, []**inst, error) {
+	// core.Simplify already expands small bounded repeats; this handles the
+	// remaining *, +, ? and unbounded-tail shapes with split/loop wiring.
+	switch {
+	case n.Min == 0 && n.Max == 1: // ?
+		start, outs, err := c.build(n.Child)
+		if err != nil {
+			return nil, nil, err
+		}
+		split := &inst{kind: instSplit, out: start}
+		return split, append(outs, &split.out1), nil
+	case n.Min == 0 && n.Max == -1: // *
+		split := &inst{kind: instSplit}
+		start, outs, err := c.build(n.Child)
+		if err != nil {
+			return nil, nil, err
+		}
+		split.out = start
+		patch(outs, split)
+		return split, []**inst{&split.out1}, nil
+	case n.Min == 1 && n.Max == -1: // +
+		start, outs, err := c.build(n.Child)
+		if err != nil {
+			return nil, nil, err
+		}
+		split := &inst{kind: instSplit, out: start}
+		patch(outs, split)
+		return start, []**inst{&split.out1}, nil
+	default:
+		return c.buildBoundedQuant(n)
+	}
+}
+
+// buildBoundedQuant handles the general {min,max} and {min,} shapes that
+// core.Simplify's default threshold deliberately leaves unexpanded (to keep
+// IR size bounded): it builds n.Min mandatory copies of the child followed
+// by either a trailing star ({min,}) or n.Max-n.Min optional copies
+// ({min,max}), compiling the child fresh for every copy so capture groups
+// inside it never alias across repetitions. This keeps NFA construction
+// working for large bounded repeats (e.g. a{100000}) without first forcing
+// core.SimplifyThreshold(ir, math.MaxInt) to unroll them in the IR, which
+// would duplicate any capture group the repeat wraps.
+func (c *nfaBuilder) buildBoundedQuant(n core.Quant) (*inst, []**inst, error) {
+	var first *inst
+	var outs []**inst
+	for i := 0; i < n.Min; i++ {
+		start, childOuts, err := c.build(n.Child)
+		if err != nil {
+			return nil, nil, err
+		}
+		if first == nil {
+			first = start
+		} else {
+			patch(outs, start)
+		}
+		outs = childOuts
+	}
+
+	if n.Max == -1 {
+		star, starOuts, err := c.buildQuant(core.Quant{Child: n.Child, Min: 0, Max: -1, Lazy: n.Lazy, Possessive: n.Possessive})
+		if err != nil {
+			return nil, nil, err
+		}
+		if first == nil {
+			return star, starOuts, nil
+		}
+		patch(outs, star)
+		return first, starOuts, nil
+	}
+
+	for i := 0; i < n.Max-n.Min; i++ {
+		start, childOuts, err := c.build(n.Child)
+		if err != nil {
+			return nil, nil, err
+		}
+		split := &inst{kind: instSplit, out: start}
+		if first == nil {
+			first = split
+		} else {
+			patch(outs, split)
+		}
+		outs = append(childOuts, &split.out1)
+	}
+	if first == nil {
+		i := &inst{kind: instSplit}
+		return i, []**inst{&i.out}, nil
+	}
+	return first, outs, nil
+}
+
+func (c *nfaBuilder) buildGroup(n core.Group) (*inst, []**inst, error) {
+	childStart, childOuts, err := c.build(n.Child)
+	if err != nil {
+		return nil, nil, err
+	}
+	if n.Name == "" {
+		return childStart, childOuts, nil
+	}
+	capStart := &inst{kind: instCapStart, capName: n.Name, out: childStart}
+	capEnd := &inst{kind: instCapEnd, capName: n.Name}
+	patch(childOuts, capEnd)
+	return capStart, []**inst{&capEnd.out}, nil
+}
+
+func (c *nfaBuilder) buildLook(n core.Look) (*inst, []**inst, error) {
+	width, ok := core.FixedWidth(n.Child)
+	if !ok {
+		kind := "lookahead"
+		if n.Behind {
+			kind = "lookbehind"
+		}
+		return nil, nil, fmt.Errorf("strling: streaming matcher does not support variable-width %s", kind)
+	}
+	sub, err := compileNFA(n.Child)
+	if err != nil {
+		return nil, nil, err
+	}
+	i := &inst{kind: instLookaround, sub: sub, behind: n.Behind, negative: n.Negative, width: width}
+	return i, []**inst{&i.out}, nil
+}
+
+func charClassPred(cc core.CharClass) func(r rune) bool {
+	return func(r rune) bool {
+		in := false
+		for _, rg := range cc.Ranges {
+			if r >= rg.Lo && r <= rg.Hi {
+				in = true
+				break
+			}
+		}
+		if cc.Negated {
+			return !in
+		}
+		return in
+	}
+}
@@ -0,0 +1,285 @@
+package strling
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/thecyberlocal/strling/bindings/go/core"
+)
+
+// Match reports the byte offsets of a single match produced by a
+// StreamMatcher, along with the byte-offset span of each named capture.
+type Match struct {
+	Start, End int
+	Groups     map[string][2]int
+}
+
+// StreamMatcher scans an io.Reader for matches of a compiled pattern
+// without loading the whole input into memory: the pattern's IR is
+// compiled to an NFA and run over a rolling window of runes sized to the
+// pattern's maximum lookbehind width.
+type StreamMatcher struct {
+	prog       *nfa
+	lookbehind int // runes of history the rolling window must retain
+}
+
+// NewStreamMatcher parses and compiles dsl and builds a streaming executor
+// for it. Patterns containing backreferences, variable-width lookbehind, or
+// variable-width lookahead are rejected here with a capability error, since
+// the streaming executor can only buffer a bounded window of input; callers
+// should fall back to the full-buffer Compile path for those patterns.
+func NewStreamMatcher(dsl string) (*StreamMatcher, error) {
+	parser := core.NewParser(dsl)
+	ast, err := parser.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := core.NewCompiler()
+	// core.Simplify's default threshold intentionally leaves large bounded
+	// repeats like a{100000} unexpanded in the IR; compileNFA's buildQuant
+	// handles those directly (compiling the child fresh per repetition)
+	// rather than forcing a full IR unroll here, which would both blow up
+	// IR size and duplicate any capture group the repeat wraps.
+	ir := core.Simplify(compiler.Compile(ast))
+
+	lookbehind := core.MaxLookbehindWidth(ir)
+	if lookbehind < 0 {
+		return nil, fmt.Errorf("strling: streaming matcher does not support variable-width lookbehind")
+	}
+
+	prog, err := compileNFA(ir)
+	if err != nil {
+		return nil, err
+	}
+	return &StreamMatcher{prog: prog, lookbehind: lookbehind}, nil
+}
+
+// Match scans r for all non-overlapping matches of the compiled pattern,
+// reporting byte offsets and named capture spans relative to the start of
+// r.
+func (m *StreamMatcher) Match(r io.Reader) ([]Match, error) {
+	e := &executor{
+		br:      bufio.NewReader(r),
+		history: make([]rune, 0, m.lookbehind),
+		maxHist: m.lookbehind,
+	}
+	return e.run(m.prog)
+}
+
+// thread is one active path through the NFA, carrying the byte offset it
+// started matching at and the capture spans recorded along the way.
+type thread struct {
+	pc    *inst
+	start int
+	caps  map[string][2]int
+}
+
+type executor struct {
+	br      *bufio.Reader
+	pos     int // byte offset of the next rune to be read
+	history []rune
+	maxHist int
+}
+
+func (e *executor) run(prog *nfa) ([]Match, error) {
+	var matches []Match
+	var clist []thread
+
+	for {
+		r, size, err := e.br.ReadRune()
+		eof := err == io.EOF
+		if err != nil && !eof {
+			return matches, err
+		}
+		_, peekErr := e.br.Peek(1)
+		atEnd := peekErr == io.EOF // r is the last rune of the input
+
+		// The pattern is unanchored, so a new thread starts at every offset.
+		clist = e.addThread(clist, prog.start, e.pos, map[string][2]int{}, nil, eof)
+		if len(clist) == 0 && eof {
+			break
+		}
+
+		var nlist []thread
+		visited := map[*inst]bool{}
+		matchedStart := -1
+		for _, th := range clist {
+			switch th.pc.kind {
+			case instChar:
+				if !eof && th.pc.pred(r) {
+					n := len(nlist)
+					nlist = e.addThread(nlist, th.pc.out, e.pos+size, th.caps, visited, atEnd)
+					for j := n; j < len(nlist); j++ {
+						nlist[j].start = th.start
+					}
+				}
+			case instMatch:
+				if matchedStart == -1 || th.start < matchedStart {
+					matchedStart = th.start
+					matches = append(matches, Match{Start: th.start, End: e.pos, Groups: th.caps})
+				}
+			}
+		}
+
+		if matchedStart != -1 {
+			// Once a thread matches, every thread that started before the
+			// match end is dropped so matches never overlap; the thread
+			// started at the match end is kept so the next match can begin
+			// there.
+			kept := nlist[:0]
+			for _, th := range nlist {
+				if th.start >= e.pos {
+					kept = append(kept, th)
+				}
+			}
+			nlist = kept
+		}
+
+		clist = nlist
+		if eof {
+			break
+		}
+
+		e.history = append(e.history, r)
+		if len(e.history) > e.maxHist {
+			e.history = e.history[len(e.history)-e.maxHist:]
+		}
+		e.pos += size
+	}
+	return matches, nil
+}
+
+// addThread follows epsilon transitions (split, capture markers, anchors,
+// lookaround assertions) from pc, appending every instChar/instMatch thread
+// it reaches to list. visited prevents the same pc from being added twice
+// within a single step, which also bounds recursion on cyclic (quantifier)
+// NFAs.
+func (e *executor) addThread(list []thread, pc *inst, pos int, caps map[string][2]int, visited map[*inst]bool, eof bool) []thread {
+	if visited == nil {
+		visited = map[*inst]bool{}
+	}
+	if pc == nil || visited[pc] {
+		return list
+	}
+	visited[pc] = true
+
+	switch pc.kind {
+	case instSplit:
+		list = e.addThread(list, pc.out, pos, caps, visited, eof)
+		list = e.addThread(list, pc.out1, pos, caps, visited, eof)
+	case instCapStart, instCapEnd:
+		next := cloneCaps(caps)
+		span := next[pc.capName]
+		if pc.kind == instCapStart {
+			span[0] = pos
+		} else {
+			span[1] = pos
+		}
+		next[pc.capName] = span
+		list = e.addThread(list, pc.out, pos, next, visited, eof)
+	case instAssertStart:
+		if pos == 0 {
+			list = e.addThread(list, pc.out, pos, caps, visited, eof)
+		}
+	case instAssertEnd:
+		if eof {
+			list = e.addThread(list, pc.out, pos, caps, visited, eof)
+		}
+	case instLookaround:
+		if e.evalLookaround(pc) {
+			list = e.addThread(list, pc.out, pos, caps, visited, eof)
+		}
+	default: // instChar, instMatch
+		list = append(list, thread{pc: pc, start: pos, caps: caps})
+	}
+	return list
+}
+
+// evalLookaround tests a fixed-width lookaround assertion against the
+// executor's rolling rune history (for lookbehind) or a forward peek at the
+// underlying reader (for lookahead), without consuming input for lookahead.
+func (e *executor) evalLookaround(pc *inst) bool {
+	var window []rune
+	if pc.behind {
+		if len(e.history) < pc.width {
+			return pc.negative
+		}
+		window = e.history[len(e.history)-pc.width:]
+	} else {
+		peeked, ok := e.peekRunes(pc.width)
+		if !ok {
+			return pc.negative
+		}
+		window = peeked
+	}
+	matched := fullMatch(pc.sub, window)
+	if pc.negative {
+		return !matched
+	}
+	return matched
+}
+
+// peekRunes returns the next n runes from the reader without consuming
+// them, or false if the reader has fewer than n runes left.
+func (e *executor) peekRunes(n int) ([]rune, bool) {
+	var bytesNeeded int
+	// Grow the peek window a rune at a time until it holds n decodable
+	// runes or the reader is exhausted.
+	for {
+		buf, _ := e.br.Peek(bytesNeeded + utf8.UTFMax)
+		runes := make([]rune, 0, n)
+		consumed := 0
+		for len(runes) < n && consumed < len(buf) {
+			r, size := utf8.DecodeRune(buf[consumed:])
+			if r == utf8.RuneError && size <= 1 {
+				break
+			}
+			runes = append(runes, r)
+			consumed += size
+		}
+		if len(runes) >= n {
+			return runes[:n], true
+		}
+		if len(buf) < bytesNeeded+utf8.UTFMax {
+			return nil, false // reader exhausted before n runes were available
+		}
+		bytesNeeded += utf8.UTFMax
+	}
+}
+
+func cloneCaps(caps map[string][2]int) map[string][2]int {
+	next := make(map[string][2]int, len(caps))
+	for k, v := range caps {
+		next[k] = v
+	}
+	return next
+}
+
+// fullMatch reports whether prog matches the entirety of runes, used to
+// evaluate the small, fixed-width sub-patterns inside lookaround
+// assertions.
+func fullMatch(prog *nfa, runes []rune) bool {
+	var clist []thread
+	e := &executor{}
+	clist = e.addThread(clist, prog.start, 0, map[string][2]int{}, nil, len(runes) == 0)
+	for i, r := range runes {
+		var nlist []thread
+		visited := map[*inst]bool{}
+		eof := i == len(runes)-1
+		for _, th := range clist {
+			if th.pc.kind == instChar && th.pc.pred(r) {
+				nlist = e.addThread(nlist, th.pc.out, i+1, th.caps, visited, eof)
+			}
+		}
+		clist = nlist
+	}
+	for _, th := range clist {
+		if th.pc.kind == instMatch {
+			return true
+		}
+	}
+	return false
+}
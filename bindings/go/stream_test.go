@@ -0,0 +1,129 @@
+package strling
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestNewStreamMatcherRejectsUnboundedLookbehind verifies that patterns the
+// streaming executor can't bound a rolling buffer for are rejected at
+// construction time with a capability error, rather than failing mid-scan.
+func TestNewStreamMatcherRejectsUnboundedLookbehind(t *testing.T) {
+	_, err := NewStreamMatcher(`(?<=a+)b`)
+	if err == nil {
+		t.Fatal("Expected error for unbounded-width lookbehind, got nil")
+	}
+}
+
+// TestNewStreamMatcherHandlesBoundedRepeat verifies that an ordinary
+// fixed-width bounded repeat (e.g. a{5}) constructs successfully even
+// though core.Simplify's default threshold leaves it as an unexpanded
+// Quant: the streaming matcher must expand it further on its own rather
+// than rejecting it as unsupported.
+func TestNewStreamMatcherHandlesBoundedRepeat(t *testing.T) {
+	sm, err := NewStreamMatcher("a{5}")
+	if err != nil {
+		t.Fatalf("NewStreamMatcher(\"a{5}\") failed: %v", err)
+	}
+
+	matches, err := sm.Match(strings.NewReader("xxaaaaax"))
+	if err != nil {
+		t.Fatalf("Match failed: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("Expected 1 match, got %d: %+v", len(matches), matches)
+	}
+	if matches[0].Start != 2 || matches[0].End != 7 {
+		t.Errorf("Expected span [2,7), got [%d,%d)", matches[0].Start, matches[0].End)
+	}
+}
+
+// TestNewStreamMatcherHandlesLargeBoundedRepeat verifies that a bounded
+// repeat well above core.Simplify's default expansion threshold still
+// constructs and matches correctly: buildQuant must expand it itself at the
+// NFA level rather than requiring the IR to be unrolled first.
+func TestNewStreamMatcherHandlesLargeBoundedRepeat(t *testing.T) {
+	sm, err := NewStreamMatcher("a{200}")
+	if err != nil {
+		t.Fatalf("NewStreamMatcher(\"a{200}\") failed: %v", err)
+	}
+
+	matches, err := sm.Match(strings.NewReader(strings.Repeat("a", 200)))
+	if err != nil {
+		t.Fatalf("Match failed: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("Expected 1 match, got %d: %+v", len(matches), matches)
+	}
+	if matches[0].Start != 0 || matches[0].End != 200 {
+		t.Errorf("Expected span [0,200), got [%d,%d)", matches[0].Start, matches[0].End)
+	}
+}
+
+// TestStreamMatcherDedupesThreadsOnSharedAlternation verifies that threads
+// reconverging on the same instruction within a step are deduplicated: a
+// pattern whose branches rejoin at the same pc (here, `(ab|ab)` repeated;
+// multi-character arms, since core.Simplify merges `a|a` into one class)
+// must not let the thread list grow with every repetition, which would
+// defeat Thompson NFA's bounded-thread-count guarantee.
+func TestStreamMatcherDedupesThreadsOnSharedAlternation(t *testing.T) {
+	sm, err := NewStreamMatcher("(ab|ab){50}c")
+	if err != nil {
+		t.Fatalf("NewStreamMatcher failed: %v", err)
+	}
+
+	matches, err := sm.Match(strings.NewReader(strings.Repeat("ab", 50) + "c"))
+	if err != nil {
+		t.Fatalf("Match failed: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("Expected 1 match, got %d: %+v", len(matches), matches)
+	}
+	if matches[0].Start != 0 || matches[0].End != 101 {
+		t.Errorf("Expected span [0,101), got [%d,%d)", matches[0].Start, matches[0].End)
+	}
+}
+
+// TestStreamMatcherDedupesThreadsAcrossNestedAlternationDiamonds stresses
+// addThread's shared-visited-set dedup with a pattern whose branches
+// reconverge at multiple nesting levels ((ab|ab) inside a repeated (x|x)):
+// without a single visited set threaded across the whole step, duplicate
+// *inst pointers pile up in nlist every repetition and thread-list growth
+// stops being bounded the way Thompson NFA construction guarantees.
+func TestStreamMatcherDedupesThreadsAcrossNestedAlternationDiamonds(t *testing.T) {
+	sm, err := NewStreamMatcher("((ab|ab)|(ab|ab)){30}c")
+	if err != nil {
+		t.Fatalf("NewStreamMatcher failed: %v", err)
+	}
+
+	matches, err := sm.Match(strings.NewReader(strings.Repeat("ab", 30) + "c"))
+	if err != nil {
+		t.Fatalf("Match failed: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("Expected 1 match, got %d: %+v", len(matches), matches)
+	}
+	if matches[0].Start != 0 || matches[0].End != 61 {
+		t.Errorf("Expected span [0,61), got [%d,%d)", matches[0].Start, matches[0].End)
+	}
+}
+
+// TestStreamMatcherFindsLiteralMatch verifies the common case: a streaming
+// match over an io.Reader finds the same span a full-buffer match would.
+func TestStreamMatcherFindsLiteralMatch(t *testing.T) {
+	sm, err := NewStreamMatcher("hello")
+	if err != nil {
+		t.Fatalf("NewStreamMatcher failed: %v", err)
+	}
+
+	matches, err := sm.Match(strings.NewReader("say hello there"))
+	if err != nil {
+		t.Fatalf("Match failed: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("Expected 1 match, got %d: %+v", len(matches), matches)
+	}
+	if matches[0].Start != 4 || matches[0].End != 9 {
+		t.Errorf("Expected span [4,9), got [%d,%d)", matches[0].Start, matches[0].End)
+	}
+}
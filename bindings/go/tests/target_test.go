@@ -0,0 +1,206 @@
+package tests
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/thecyberlocal/strling/bindings/go/core"
+	"github.com/thecyberlocal/strling/bindings/go/emitters"
+)
+
+// TestEmitForNamedGroupSyntax verifies that named groups are rendered with
+// the target engine's own syntax rather than always `(?P<name>...)`.
+func TestEmitForNamedGroupSyntax(t *testing.T) {
+	testCases := []struct {
+		name   string
+		target emitters.Target
+		want   string
+	}{
+		{"GoRE2", emitters.TargetGoRE2, "(?P<id>abc)"},
+		{"PythonRe", emitters.TargetPythonRe, "(?P<id>abc)"},
+		{"ECMAScript", emitters.TargetECMAScript, "(?<id>abc)"},
+		{"DotNet", emitters.TargetDotNet, "(?<id>abc)"},
+	}
+
+	dsl := "(?<id>abc)"
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parser := core.NewParser(dsl)
+			ast, err := parser.Parse()
+			if err != nil {
+				t.Fatalf("Parser failed: %v", err)
+			}
+			compiler := core.NewCompiler()
+			ir := compiler.Compile(ast)
+
+			got, err := emitters.EmitFor(ir, core.Flags{}, tc.target)
+			if err != nil {
+				t.Fatalf("EmitFor failed: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("EmitFor(%s) = %q, want %q", tc.target, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestEmitForAnchoredDotPattern verifies that start/end anchors and `.` are
+// emitted rather than rejected, using `\A`/`\z` or `^`/`$` per the target's
+// StringAnchors capability.
+func TestEmitForAnchoredDotPattern(t *testing.T) {
+	testCases := []struct {
+		name   string
+		target emitters.Target
+		want   string
+	}{
+		{"GoRE2", emitters.TargetGoRE2, "^a.c$"},
+		{"PCRE", emitters.TargetPCRE, `\Aa.c\z`},
+		{"ECMAScript", emitters.TargetECMAScript, "^a.c$"},
+	}
+
+	dsl := "^a.c$"
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parser := core.NewParser(dsl)
+			ast, err := parser.Parse()
+			if err != nil {
+				t.Fatalf("Parser failed: %v", err)
+			}
+			compiler := core.NewCompiler()
+			ir := compiler.Compile(ast)
+
+			got, err := emitters.EmitFor(ir, core.Flags{}, tc.target)
+			if err != nil {
+				t.Fatalf("EmitFor failed: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("EmitFor(%s) = %q, want %q", tc.target, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestEmitForVariableWidthLookbehindRejectedOnRE2 verifies that a
+// variable-width lookbehind is rejected for engines that require fixed
+// width lookbehind bodies, with a diagnostic naming the offending feature.
+func TestEmitForVariableWidthLookbehindRejectedOnRE2(t *testing.T) {
+	dsl := `(?<=a+)b`
+	parser := core.NewParser(dsl)
+	ast, err := parser.Parse()
+	if err != nil {
+		t.Fatalf("Parser failed: %v", err)
+	}
+	compiler := core.NewCompiler()
+	ir := compiler.Compile(ast)
+
+	_, err = emitters.EmitFor(ir, core.Flags{}, emitters.TargetGoRE2)
+	if err == nil {
+		t.Fatal("Expected error for variable-width lookbehind on RE2, got nil")
+	}
+	if !strings.Contains(err.Error(), "variable-width lookbehind") {
+		t.Errorf("Expected error to mention variable-width lookbehind, got: %v", err)
+	}
+}
+
+// TestEmitForRejectsLookbehindEntirelyWhereUnsupported verifies lookbehind
+// of any width is rejected for engines lacking lookbehind support at all.
+func TestEmitForRejectsLookbehindEntirelyWhereUnsupported(t *testing.T) {
+	dsl := `(?<=a)b`
+	parser := core.NewParser(dsl)
+	ast, err := parser.Parse()
+	if err != nil {
+		t.Fatalf("Parser failed: %v", err)
+	}
+	compiler := core.NewCompiler()
+	ir := compiler.Compile(ast)
+
+	_, err = emitters.EmitFor(ir, core.Flags{}, emitters.TargetGoRE2)
+	if err == nil {
+		t.Fatal("Expected error for lookbehind on RE2, got nil")
+	}
+}
+
+// TestEmitForRejectsLookaheadWhereUnsupported verifies lookahead is rejected
+// for engines lacking lookaround support entirely (RE2), not just
+// lookbehind.
+func TestEmitForRejectsLookaheadWhereUnsupported(t *testing.T) {
+	dsl := `a(?=b)`
+	parser := core.NewParser(dsl)
+	ast, err := parser.Parse()
+	if err != nil {
+		t.Fatalf("Parser failed: %v", err)
+	}
+	compiler := core.NewCompiler()
+	ir := compiler.Compile(ast)
+
+	_, err = emitters.EmitFor(ir, core.Flags{}, emitters.TargetGoRE2)
+	if err == nil {
+		t.Fatal("Expected error for lookahead on RE2, got nil")
+	}
+	if !strings.Contains(err.Error(), "lookahead") {
+		t.Errorf("Expected error to mention lookahead, got: %v", err)
+	}
+
+	// A target with lookaround support must still accept it.
+	got, err := emitters.EmitFor(ir, core.Flags{}, emitters.TargetPCRE)
+	if err != nil {
+		t.Fatalf("EmitFor(PCRE) failed: %v", err)
+	}
+	if got != "a(?=b)" {
+		t.Errorf("EmitFor(PCRE) = %q, want %q", got, "a(?=b)")
+	}
+}
+
+// TestEmitForEscapesLiteralMetacharacters verifies that literal text
+// containing regex metacharacters is escaped on emission, so it matches
+// itself rather than being parsed as syntax.
+func TestEmitForEscapesLiteralMetacharacters(t *testing.T) {
+	dsl := `1\+1=2\?`
+	parser := core.NewParser(dsl)
+	ast, err := parser.Parse()
+	if err != nil {
+		t.Fatalf("Parser failed: %v", err)
+	}
+	compiler := core.NewCompiler()
+	ir := compiler.Compile(ast)
+
+	got, err := emitters.EmitFor(ir, core.Flags{}, emitters.TargetGoRE2)
+	if err != nil {
+		t.Fatalf("EmitFor failed: %v", err)
+	}
+	want := `1\+1=2\?`
+	if got != want {
+		t.Errorf("EmitFor = %q, want %q", got, want)
+	}
+}
+
+// TestEmitForEscapedLiteralDotMatchesOnlyDot verifies, semantically rather
+// than just by string comparison, that a literal `.` is escaped rather than
+// emitted as the "any character" metacharacter: compiling the emitted
+// pattern must match a literal dot and reject an unrelated character.
+func TestEmitForEscapedLiteralDotMatchesOnlyDot(t *testing.T) {
+	dsl := `a\.b`
+	parser := core.NewParser(dsl)
+	ast, err := parser.Parse()
+	if err != nil {
+		t.Fatalf("Parser failed: %v", err)
+	}
+	compiler := core.NewCompiler()
+	ir := compiler.Compile(ast)
+
+	pattern, err := emitters.EmitFor(ir, core.Flags{}, emitters.TargetGoRE2)
+	if err != nil {
+		t.Fatalf("EmitFor failed: %v", err)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		t.Fatalf("regexp.Compile(%q) failed: %v", pattern, err)
+	}
+	if !re.MatchString("a.b") {
+		t.Errorf("pattern %q should match literal \"a.b\"", pattern)
+	}
+	if re.MatchString("aXb") {
+		t.Errorf("pattern %q should not match \"aXb\" (literal `.` must not act as any-char)", pattern)
+	}
+}